@@ -0,0 +1,171 @@
+// Package acme implements an issuer.CertificateSource backed by an ACME
+// server, porting the issuer onto the same Obtain contract as the Vault,
+// SelfSigned and CFSSL sources. It drives authorization of every DNS name
+// on the Certificate, solving each challenge with whichever
+// ChallengeSolver the Certificate's acme.config selects for that domain,
+// then finalizes the order with a CSR built by the shared issuer helpers.
+// Only TLS-ALPN-01 has a ChallengeSolver in this package: Obtain returns an
+// error for a domain whose acme.config entry selects HTTP-01.
+package acme
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/acme"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// ACME is an issuer.CertificateSource that obtains certificates from an
+// ACME server.
+type ACME struct {
+	Client     *acme.Client
+	KubeClient kubernetes.Interface
+}
+
+// New returns an ACME CertificateSource using client to talk to the ACME
+// server and kubeClient to run challenge solver presenters.
+func New(client *acme.Client, kubeClient kubernetes.Interface) *ACME {
+	return &ACME{Client: client, KubeClient: kubeClient}
+}
+
+// Issue obtains a signed certificate for crt from the ACME server,
+// satisfying the Issue-style callers used elsewhere in the controller.
+func (a *ACME) Issue(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	return issuer.Issue(ctx, a.KubeClient, a, crt)
+}
+
+// SupportsOCSPStapling implements issuer.OCSPStaplingSource: ACME certificates
+// carry a real OCSP responder URL in their AIA extension.
+func (a *ACME) SupportsOCSPStapling() bool {
+	return true
+}
+
+// Obtain implements issuer.CertificateSource.
+func (a *ACME) Obtain(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	altNames := crt.Spec.DNSNames
+	if len(altNames) == 0 {
+		return nil, nil, fmt.Errorf("no dns names specified on certificate")
+	}
+
+	order, err := a.Client.AuthorizeOrder(ctx, acme.DomainIDs(altNames...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating ACME order: %s", err.Error())
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := a.solveAuthorization(ctx, crt, authzURL); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	key, err := issuer.GeneratePrivateKey(crt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key: %s", err.Error())
+	}
+
+	csrPEM, err := issuer.BuildCSR(crt, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrDER, _ := pem.Decode(csrPEM)
+
+	certDER, _, err := a.Client.CreateOrderCert(ctx, order.FinalizeURL, csrDER.Bytes, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finalizing ACME order: %s", err.Error())
+	}
+
+	keyPEM, err := pki.EncodePrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding private key: %s", err.Error())
+	}
+
+	return keyPEM, encodeCertChain(certDER), nil
+}
+
+// solveAuthorization fetches authzURL, presents and waits on the challenge
+// selected for its domain by acme.config, and cleans up the presenter
+// afterwards regardless of outcome.
+func (a *ACME) solveAuthorization(ctx context.Context, crt *v1alpha1.Certificate, authzURL string) error {
+	authz, err := a.Client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("error fetching ACME authorization: %s", err.Error())
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	domain := authz.Identifier.Value
+
+	wantType, err := challengeTypeFor(crt, domain)
+	if err != nil {
+		return err
+	}
+
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == wantType {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("no %s challenge offered for domain %q", wantType, domain)
+	}
+
+	solver, err := solverFor(crt, domain, a.KubeClient)
+	if err != nil {
+		return err
+	}
+
+	keyAuth, err := a.Client.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return fmt.Errorf("error computing key authorization: %s", err.Error())
+	}
+
+	if err := solver.Present(crt, domain, keyAuth); err != nil {
+		return fmt.Errorf("error presenting %s challenge for %q: %s", wantType, domain, err.Error())
+	}
+	defer solver.CleanUp(crt, domain, keyAuth)
+
+	if _, err := a.Client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("error accepting %s challenge for %q: %s", wantType, domain, err.Error())
+	}
+
+	if _, err := a.Client.WaitAuthorization(ctx, authzURL); err != nil {
+		return fmt.Errorf("error waiting for authorization of %q: %s", domain, err.Error())
+	}
+
+	return nil
+}
+
+// challengeTypeFor returns the ACME challenge type string selected for
+// domain by crt's acme.config.
+func challengeTypeFor(crt *v1alpha1.Certificate, domain string) (string, error) {
+	cfg, ok := domainConfig(crt, domain)
+	if !ok {
+		return "", fmt.Errorf("no acme.config entry found for domain %q", domain)
+	}
+
+	switch {
+	case cfg.TLSALPN01 != nil:
+		return "tls-alpn-01", nil
+	case cfg.HTTP01 != nil:
+		return "http-01", nil
+	default:
+		return "", fmt.Errorf("acme.config entry for domain %q sets no challenge type", domain)
+	}
+}
+
+func encodeCertChain(der [][]byte) []byte {
+	var out []byte
+	for _, c := range der {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c})...)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package acme
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/tlsalpn01"
+)
+
+// ChallengeSolver is implemented by every ACME challenge mechanism the ACME
+// issuer supports (currently only TLS-ALPN-01; HTTP-01/DNS-01 live in
+// sibling packages not present in this change).
+type ChallengeSolver interface {
+	Present(crt *v1alpha1.Certificate, domain, keyAuthorization string) error
+	CleanUp(crt *v1alpha1.Certificate, domain, keyAuthorization string) error
+}
+
+// solverFor returns the ChallengeSolver configured for domain on crt, by
+// looking up the acme.config entry whose Domains list contains it and
+// inspecting which challenge-specific config is set, the same way the
+// HTTP-01 path already selects its solver from acmeIngressClass.
+func solverFor(crt *v1alpha1.Certificate, domain string, client kubernetes.Interface) (ChallengeSolver, error) {
+	cfg, ok := domainConfig(crt, domain)
+	if !ok {
+		return nil, fmt.Errorf("no acme.config entry found for domain %q", domain)
+	}
+
+	switch {
+	case cfg.TLSALPN01 != nil:
+		return tlsalpn01.NewSolver(client), nil
+	case cfg.HTTP01 != nil:
+		return nil, fmt.Errorf("http-01 challenges are solved outside this package")
+	default:
+		return nil, fmt.Errorf("acme.config entry for domain %q sets no challenge type", domain)
+	}
+}
+
+// domainConfig returns the acme.config entry that lists domain, if any.
+func domainConfig(crt *v1alpha1.Certificate, domain string) (v1alpha1.ACMECertificateDomainConfig, bool) {
+	for _, cfg := range crt.Spec.ACME.Config {
+		for _, d := range cfg.Domains {
+			if d == domain {
+				return cfg, true
+			}
+		}
+	}
+	return v1alpha1.ACMECertificateDomainConfig{}, false
+}
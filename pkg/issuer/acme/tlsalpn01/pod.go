@@ -0,0 +1,120 @@
+package tlsalpn01
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+const (
+	acmeSolverImage = "quay.io/jetstack/cert-manager-acmesolver:latest"
+	acmeSolverPort  = 443
+)
+
+// ensureSecret creates the tls.Secret holding the challenge certificate
+// generated for domain, or overwrites the data of the existing one.
+func (s *Solver) ensureSecret(crt *v1alpha1.Certificate, domain string, certPEM, keyPEM []byte) (*corev1.Secret, error) {
+	name := presenterName(crt, domain)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: crt.Namespace,
+			Labels:    presenterLabels(crt, domain),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+
+	existing, err := s.client.CoreV1().Secrets(crt.Namespace).Get(name, metav1.GetOptions{})
+	if err == nil {
+		existing.Data = secret.Data
+		return s.client.CoreV1().Secrets(crt.Namespace).Update(existing)
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return s.client.CoreV1().Secrets(crt.Namespace).Create(secret)
+}
+
+// ensurePod creates, or returns the existing, presenter Pod for domain. The
+// Pod runs the acmesolver binary, which terminates TLS connections
+// requesting the acme-tls/1 ALPN protocol using the certificate stored in
+// secretName, and otherwise proxies/falls through to normal traffic.
+func (s *Solver) ensurePod(crt *v1alpha1.Certificate, domain, secretName string) (*corev1.Pod, error) {
+	name := presenterName(crt, domain)
+
+	existing, err := s.client.CoreV1().Pods(crt.Namespace).Get(name, metav1.GetOptions{})
+	if err == nil {
+		return existing, nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: crt.Namespace,
+			Labels:    presenterLabels(crt, domain),
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+			Containers: []corev1.Container{
+				{
+					Name:  "acmesolver",
+					Image: acmeSolverImage,
+					Args: []string{
+						fmt.Sprintf("--tls-alpn-01-port=%d", acmeSolverPort),
+						fmt.Sprintf("--tls-alpn-01-domain=%s", domain),
+					},
+					Ports: []corev1.ContainerPort{
+						{Name: "https", ContainerPort: acmeSolverPort},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "certs", MountPath: "/etc/acme-tls-alpn", ReadOnly: true},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "certs",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+					},
+				},
+			},
+		},
+	}
+
+	return s.client.CoreV1().Pods(crt.Namespace).Create(pod)
+}
+
+func presenterLabels(crt *v1alpha1.Certificate, domain string) map[string]string {
+	return map[string]string{
+		podLabelKey: domainLabelValue(crt, domain),
+	}
+}
+
+// presenterName derives a deterministic, DNS-label safe resource name for
+// the Pod/Service/Secret created to solve the challenge for domain, scoped
+// to the owning Certificate so repeated reconciles are idempotent.
+func presenterName(crt *v1alpha1.Certificate, domain string) string {
+	sum := sha256.Sum256([]byte(crt.Namespace + "/" + crt.Name + "/" + domain))
+	return presenterPrefix + hex.EncodeToString(sum[:])[:16]
+}
+
+func domainLabelValue(crt *v1alpha1.Certificate, domain string) string {
+	sum := sha256.Sum256([]byte(domain))
+	return hex.EncodeToString(sum[:])[:16]
+}
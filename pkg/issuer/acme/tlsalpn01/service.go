@@ -0,0 +1,41 @@
+package tlsalpn01
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+// ensureService creates, or returns the existing, Service that exposes the
+// presenter Pod on port 443 so it can receive the TLS-ALPN-01 validation
+// connection from the ACME server.
+func (s *Solver) ensureService(crt *v1alpha1.Certificate, domain string, pod *corev1.Pod) (*corev1.Service, error) {
+	name := presenterName(crt, domain)
+
+	existing, err := s.client.CoreV1().Services(crt.Namespace).Get(name, metav1.GetOptions{})
+	if err == nil {
+		return existing, nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: crt.Namespace,
+			Labels:    presenterLabels(crt, domain),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: presenterLabels(crt, domain),
+			Ports: []corev1.ServicePort{
+				{Name: "https", Port: acmeSolverPort, TargetPort: intstr.FromInt(acmeSolverPort)},
+			},
+		},
+	}
+
+	return s.client.CoreV1().Services(crt.Namespace).Create(svc)
+}
@@ -0,0 +1,99 @@
+package tlsalpn01
+
+import (
+	"fmt"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+const (
+	podLabelKey     = "certmanager.k8s.io/acme-http-domain"
+	presenterPrefix = "cm-acme-tls-alpn-"
+)
+
+// Solver presents TLS-ALPN-01 challenges by running a dedicated Pod+Service
+// pair that terminates TLS on port 443 for connections that request the
+// acme-tls/1 ALPN protocol, and serves the self-signed challenge
+// certificate generated by GenerateChallengeCertificate.
+type Solver struct {
+	client kubernetes.Interface
+}
+
+// NewSolver returns a new TLS-ALPN-01 Solver backed by the given Kubernetes
+// clientset.
+func NewSolver(client kubernetes.Interface) *Solver {
+	return &Solver{client: client}
+}
+
+// Present creates the Pod and Service used to respond to a TLS-ALPN-01
+// challenge for the given domain, configuring them to serve a certificate
+// built from keyAuthorization whenever a TLS handshake offers the
+// acme-tls/1 ALPN protocol.
+func (s *Solver) Present(crt *v1alpha1.Certificate, domain, keyAuthorization string) error {
+	certPEM, keyPEM, err := GenerateChallengeCertificate(domain, keyAuthorization)
+	if err != nil {
+		return fmt.Errorf("error generating TLS-ALPN-01 challenge certificate: %s", err.Error())
+	}
+
+	secret, err := s.ensureSecret(crt, domain, certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("error ensuring challenge secret: %s", err.Error())
+	}
+
+	pod, err := s.ensurePod(crt, domain, secret.Name)
+	if err != nil {
+		return fmt.Errorf("error ensuring challenge pod: %s", err.Error())
+	}
+
+	if _, err := s.ensureService(crt, domain, pod); err != nil {
+		return fmt.Errorf("error ensuring challenge service: %s", err.Error())
+	}
+
+	return nil
+}
+
+// CleanUp removes any Pod, Service and Secret resources created by Present
+// for the given domain.
+func (s *Solver) CleanUp(crt *v1alpha1.Certificate, domain, keyAuthorization string) error {
+	selector := s.labelSelector(crt, domain)
+
+	pods, err := s.client.CoreV1().Pods(crt.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, p := range pods.Items {
+		if err := s.client.CoreV1().Pods(crt.Namespace).Delete(p.Name, nil); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	svcs, err := s.client.CoreV1().Services(crt.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, svc := range svcs.Items {
+		if err := s.client.CoreV1().Services(crt.Namespace).Delete(svc.Name, nil); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	secrets, err := s.client.CoreV1().Secrets(crt.Namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, sec := range secrets.Items {
+		if err := s.client.CoreV1().Secrets(crt.Namespace).Delete(sec.Name, nil); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Solver) labelSelector(crt *v1alpha1.Certificate, domain string) string {
+	return fmt.Sprintf("%s=%s", podLabelKey, domainLabelValue(crt, domain))
+}
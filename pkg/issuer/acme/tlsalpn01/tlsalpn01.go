@@ -0,0 +1,74 @@
+package tlsalpn01
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// ACMETLS1Protocol is the ALPN protocol name that a client must request via
+// the TLS ClientHello for a TLS-ALPN-01 challenge to be presented, as
+// defined in RFC 8737.
+const ACMETLS1Protocol = "acme-tls/1"
+
+// idPeACMEIdentifier is the OID of the id-pe-acmeIdentifier certificate
+// extension used to carry the SHA-256 digest of the key authorization.
+var idPeACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+// certificateDuration is the validity period of the self-signed
+// challenge certificate. It only needs to live long enough for the ACME
+// server to complete validation.
+const certificateDuration = time.Minute * 10
+
+// GenerateChallengeCertificate returns a self-signed TLS certificate for
+// domain whose id-pe-acmeIdentifier extension contains the SHA-256 digest
+// of keyAuthorization, as required for a TLS-ALPN-01 challenge response.
+func GenerateChallengeCertificate(domain, keyAuthorization string) (certPEM, keyPEM []byte, err error) {
+	key, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key for TLS-ALPN-01 challenge certificate: %s", err.Error())
+	}
+
+	digest := sha256.Sum256([]byte(keyAuthorization))
+	digestASN1, err := asn1.Marshal(digest[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding acmeIdentifier extension value: %s", err.Error())
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating certificate serial number: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(certificateDuration),
+		ExtraExtensions: []pkix.Extension{
+			{
+				Id:       idPeACMEIdentifier,
+				Critical: true,
+				Value:    digestASN1,
+			},
+		},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating TLS-ALPN-01 challenge certificate: %s", err.Error())
+	}
+
+	certPEM = pki.EncodeX509(certDER)
+	keyPEM = pki.EncodePKCS1PrivateKey(key)
+
+	return certPEM, keyPEM, nil
+}
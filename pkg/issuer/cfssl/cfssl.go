@@ -0,0 +1,104 @@
+// Package cfssl implements an issuer.CertificateSource backed by a remote
+// CFSSL signing server (https://github.com/cloudflare/cfssl), following the
+// same Obtain contract as the Vault and ACME sources.
+package cfssl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const keyBitSize = 2048
+
+// CFSSL is an issuer.CertificateSource that requests signed certificates
+// from a remote CFSSL server's /api/v1/cfssl/sign endpoint.
+type CFSSL struct {
+	// ServerURL is the base URL of the CFSSL signing server, e.g.
+	// "https://cfssl.example.com".
+	ServerURL string
+
+	httpClient *http.Client
+	kubeClient kubernetes.Interface
+}
+
+// New returns a CFSSL CertificateSource that signs against the server at
+// serverURL. kubeClient is passed on to issuer.Issue; CFSSL does not
+// implement issuer.OCSPStaplingSource, so no OCSP staple is written for
+// its certificates even when they request OCSPMustStaple.
+func New(serverURL string, kubeClient kubernetes.Interface) *CFSSL {
+	return &CFSSL{ServerURL: serverURL, httpClient: http.DefaultClient, kubeClient: kubeClient}
+}
+
+// Issue obtains a signed certificate for crt, satisfying the Issue-style
+// callers used elsewhere in the controller.
+func (c *CFSSL) Issue(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	return issuer.Issue(ctx, c.kubeClient, c, crt)
+}
+
+type signRequest struct {
+	CertificateRequest string `json:"certificate_request"`
+}
+
+type signResponse struct {
+	Success bool `json:"success"`
+	Result  struct {
+		Certificate string `json:"certificate"`
+	} `json:"result"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Obtain implements issuer.CertificateSource.
+func (c *CFSSL) Obtain(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	key, err := pki.GenerateRSAPrivateKey(keyBitSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key: %s", err.Error())
+	}
+
+	csrPEM, err := issuer.BuildCSR(crt, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := json.Marshal(signRequest{CertificateRequest: string(csrPEM)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding CFSSL sign request: %s", err.Error())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.ServerURL+"/api/v1/cfssl/sign", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building CFSSL sign request: %s", err.Error())
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error calling CFSSL server: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	var result signResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("error decoding CFSSL sign response: %s", err.Error())
+	}
+
+	if !result.Success {
+		if len(result.Errors) > 0 {
+			return nil, nil, fmt.Errorf("CFSSL server returned an error: %s", result.Errors[0].Message)
+		}
+		return nil, nil, fmt.Errorf("CFSSL server returned an unsuccessful response")
+	}
+
+	return pki.EncodePKCS1PrivateKey(key), []byte(result.Result.Certificate), nil
+}
@@ -0,0 +1,48 @@
+package ocsp
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// ScheduleRefresh runs an initial Staple for leaf/issuerCert and reschedules
+// itself at the interval Staple reports, for as long as the Stapler lives.
+// Failures are logged and retried after minimumRefreshInterval rather than
+// aborting the loop, since a transient OCSP responder outage shouldn't stop
+// future staple attempts.
+//
+// The refresh loop is keyed by namespace/secretName rather than tied to the
+// caller's context: calling ScheduleRefresh again for the same Secret (e.g.
+// on renewal) cancels the previous loop and starts a fresh one, instead of
+// leaking an extra goroutine per call.
+func (s *Stapler) ScheduleRefresh(namespace, secretName string, leaf, issuerCert *x509.Certificate) {
+	key := namespace + "/" + secretName
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	if previousCancel, ok := s.cancels[key]; ok {
+		previousCancel()
+	}
+	s.cancels[key] = cancel
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			refresh, err := s.Staple(namespace, secretName, leaf, issuerCert)
+			if err != nil {
+				glog.Errorf("error stapling OCSP response for %s/%s: %s", namespace, secretName, err.Error())
+				refresh = minimumRefreshInterval
+			}
+
+			select {
+			case <-time.After(refresh):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
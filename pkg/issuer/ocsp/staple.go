@@ -0,0 +1,101 @@
+// Package ocsp implements an OCSP stapling controller: once a certificate
+// has been issued, it fetches a stapled OCSP response from the
+// certificate's AIA OCSP responder, verifies it, and stores it in the
+// target Secret alongside the certificate and key so that servers using
+// the Secret can staple it directly.
+package ocsp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretOCSPStapleKey is the Secret data key that the stapled OCSP response
+// is stored under, alongside the usual tls.crt/tls.key keys.
+const SecretOCSPStapleKey = "tls.ocsp-staple"
+
+// minimumRefreshInterval bounds how soon a staple is re-fetched even if the
+// responder returns a very short validity window, to avoid hammering it.
+const minimumRefreshInterval = time.Minute
+
+// Stapler fetches and refreshes OCSP staples for issued certificates,
+// writing them into the same Secret that holds the certificate and key.
+// A Stapler is process-scoped: it should be created once and reused for
+// every issuance, so that ScheduleRefresh can recognise and replace the
+// refresh loop for a Secret it is already refreshing.
+type Stapler struct {
+	client     kubernetes.Interface
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	cancels map[string]func()
+}
+
+// NewStapler returns a Stapler that writes staples via the given clientset.
+func NewStapler(client kubernetes.Interface) *Stapler {
+	return &Stapler{client: client, httpClient: http.DefaultClient, cancels: map[string]func(){}}
+}
+
+// Staple fetches an OCSP response for leaf (issued by issuerCert), verifies
+// it, and writes it into the Secret named secretName. It returns the
+// duration after which the staple should be refreshed.
+func (s *Stapler) Staple(namespace, secretName string, leaf, issuerCert *x509.Certificate) (time.Duration, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return 0, fmt.Errorf("certificate has no OCSP responder URL in its AIA extension")
+	}
+
+	req, err := ocsp.CreateRequest(leaf, issuerCert, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating OCSP request: %s", err.Error())
+	}
+
+	resp, err := s.httpClient.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return 0, fmt.Errorf("error requesting OCSP response: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error reading OCSP response: %s", err.Error())
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(respBytes, leaf, issuerCert)
+	if err != nil {
+		return 0, fmt.Errorf("error verifying OCSP response: %s", err.Error())
+	}
+
+	if parsed.Status != ocsp.Good {
+		return 0, fmt.Errorf("OCSP responder returned non-good status: %d", parsed.Status)
+	}
+
+	secret, err := s.client.CoreV1().Secrets(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("error fetching target secret %s/%s: %s", namespace, secretName, err.Error())
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[SecretOCSPStapleKey] = respBytes
+
+	if _, err := s.client.CoreV1().Secrets(namespace).Update(secret); err != nil {
+		return 0, fmt.Errorf("error writing OCSP staple to secret %s/%s: %s", namespace, secretName, err.Error())
+	}
+
+	refresh := time.Until(parsed.NextUpdate) / 2
+	if refresh < minimumRefreshInterval {
+		refresh = minimumRefreshInterval
+	}
+
+	return refresh, nil
+}
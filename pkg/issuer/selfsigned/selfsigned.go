@@ -0,0 +1,84 @@
+// Package selfsigned implements an issuer.CertificateSource that signs
+// certificates using a local, in-memory CA key rather than calling out to
+// an external service such as Vault or an ACME server. It is primarily
+// useful for development, testing, and internal-only certificates.
+package selfsigned
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// certificateDuration of 90 days if the Certificate's spec.duration is not set
+	certificateDuration = time.Hour * 24 * 90
+
+	keyBitSize = 2048
+)
+
+// SelfSigned is an issuer.CertificateSource that signs certificates using
+// its own CA key and certificate, rather than an external CA.
+type SelfSigned struct {
+	caKey      *rsa.PrivateKey
+	caCert     *x509.Certificate
+	kubeClient kubernetes.Interface
+}
+
+// New returns a SelfSigned CertificateSource that signs issued certificates
+// using the given CA key and certificate. kubeClient is passed on to
+// issuer.Issue; SelfSigned does not implement issuer.OCSPStaplingSource,
+// so no OCSP staple is written for its certificates.
+func New(caKey *rsa.PrivateKey, caCert *x509.Certificate, kubeClient kubernetes.Interface) *SelfSigned {
+	return &SelfSigned{caKey: caKey, caCert: caCert, kubeClient: kubeClient}
+}
+
+// Issue obtains a signed certificate for crt, satisfying the Issue-style
+// callers used elsewhere in the controller.
+func (s *SelfSigned) Issue(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	return issuer.Issue(ctx, s.kubeClient, s, crt)
+}
+
+// Obtain implements issuer.CertificateSource.
+func (s *SelfSigned) Obtain(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	key, err := pki.GenerateRSAPrivateKey(keyBitSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating private key: %s", err.Error())
+	}
+
+	commonName, err := pki.CommonNameForCertificate(crt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting Common Name from certificate: %s", err.Error())
+	}
+
+	altNames, err := pki.DNSNamesForCertificate(crt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error extracting DNS Names from certificate: %s", err.Error())
+	}
+
+	if len(commonName) == 0 && len(altNames) > 0 {
+		commonName = altNames[0]
+	}
+
+	duration := certificateDuration
+	if crt.Spec.Duration != 0 {
+		duration = crt.Spec.Duration
+	}
+
+	template := pki.GenerateCSR(commonName, altNames...)
+
+	certDER, err := pki.SignCertificate(template, s.caCert, &key.PublicKey, s.caKey, duration)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error signing certificate: %s", err.Error())
+	}
+
+	return pki.EncodePKCS1PrivateKey(key), pki.EncodeX509(certDER), nil
+}
@@ -0,0 +1,34 @@
+package issuer
+
+import (
+	"context"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+// CertificateSource obtains signed certificates for a Certificate resource
+// from a single backend (e.g. ACME, Vault, a local CA). It is a narrower
+// replacement for the Issue method previously implemented directly on each
+// issuer type, so that new backends can be added without teaching the
+// certificate controller anything new.
+type CertificateSource interface {
+	// Obtain returns a PEM encoded private key and PEM encoded certificate
+	// chain for crt, or an error if one could not be obtained.
+	Obtain(ctx context.Context, crt *v1alpha1.Certificate) (keyPEM, chainPEM []byte, err error)
+}
+
+// Revoker is implemented by CertificateSources that support revoking a
+// previously issued certificate. Sources that cannot revoke (e.g. because
+// the backing CA has no revocation API) simply do not implement it.
+type Revoker interface {
+	Revoke(ctx context.Context, crt *v1alpha1.Certificate) error
+}
+
+// OCSPStaplingSource is implemented by CertificateSources whose issued
+// certificates carry a real OCSP responder URL that can be queried for a
+// staple, such as ACME and Vault. Sources that cannot support stapling
+// (e.g. SelfSigned, which has no OCSP responder to query) simply do not
+// implement it, so Issue skips scheduling a staple refresh for them.
+type OCSPStaplingSource interface {
+	SupportsOCSPStapling() bool
+}
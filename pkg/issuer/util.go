@@ -0,0 +1,216 @@
+package issuer
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	"github.com/jetstack/cert-manager/pkg/issuer/ocsp"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	errorIssueCert = "ErrIssueCert"
+
+	successCertIssued = "CertIssueSuccess"
+
+	messageErrorIssueCert = "Error issuing TLS certificate: "
+
+	messageCertIssued = "Certificate issued successfully"
+
+	defaultOrganization = "cert-manager"
+
+	// defaultKeyBitSize is the RSA key size used when crt.Spec.KeySize is
+	// unset.
+	defaultKeyBitSize = 2048
+)
+
+// idPeTLSFeature is the OID of the RFC 7633 TLS Feature extension, used to
+// signal the "must-staple" OCSP requirement to clients.
+var idPeTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+
+// statusRequestTLSFeature is the TLS Feature value for status_request
+// (OCSP stapling), as registered in RFC 6066.
+const statusRequestTLSFeature = 5
+
+// mustStapleExtension returns the CSR extension that signals to a CA it
+// should embed the must-staple TLS Feature extension in the issued
+// certificate.
+func mustStapleExtension() (pkix.Extension, error) {
+	value, err := asn1.Marshal([]int{statusRequestTLSFeature})
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("error encoding TLS Feature extension value: %s", err.Error())
+	}
+
+	return pkix.Extension{Id: idPeTLSFeature, Value: value}, nil
+}
+
+// GeneratePrivateKey creates a new private key of the type and size/curve
+// requested on crt.Spec, defaulting to a 2048 bit RSA key for certificates
+// that don't set keyAlgorithm. It is used by the ACME and Vault sources;
+// the SelfSigned and CFSSL sources always generate a 2048 bit RSA key and
+// ignore keyAlgorithm and keySize.
+func GeneratePrivateKey(crt *v1alpha1.Certificate) (crypto.Signer, error) {
+	switch crt.Spec.KeyAlgorithm {
+	case v1alpha1.ECDSAKeyAlgorithm:
+		return pki.GenerateECDSAPrivateKey(curveForKeySize(crt.Spec.KeySize))
+	case v1alpha1.RSAKeyAlgorithm, "":
+		keySize := defaultKeyBitSize
+		if crt.Spec.KeySize > 0 {
+			keySize = crt.Spec.KeySize
+		}
+		return pki.GenerateRSAPrivateKey(keySize)
+	default:
+		return nil, fmt.Errorf("unsupported keyAlgorithm %q", crt.Spec.KeyAlgorithm)
+	}
+}
+
+func curveForKeySize(keySize int) elliptic.Curve {
+	switch keySize {
+	case 384:
+		return elliptic.P384()
+	case 521:
+		return elliptic.P521()
+	default:
+		return elliptic.P256()
+	}
+}
+
+// BuildCSR constructs a PEM encoded x509 certificate request for crt, signed
+// with key, using the common name and DNS names configured on the
+// Certificate resource. It is shared by the ACME, Vault and CFSSL sources so
+// that CSR construction (and any additions such as OCSP must-staple) only
+// need to be implemented once. The SelfSigned source builds its own
+// template with pki.GenerateCSR and does not add the must-staple extension.
+func BuildCSR(crt *v1alpha1.Certificate, key crypto.Signer) ([]byte, error) {
+	commonName, err := pki.CommonNameForCertificate(crt)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting Common Name from certificate: %s", err.Error())
+	}
+
+	altNames, err := pki.DNSNamesForCertificate(crt)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting DNS Names from certificate: %s", err.Error())
+	}
+
+	if len(commonName) == 0 && len(altNames) > 0 {
+		commonName = altNames[0]
+	}
+
+	template := pki.GenerateCSR(commonName, altNames...)
+	template.Subject.Organization = []string{defaultOrganization}
+
+	if crt.Spec.OCSPMustStaple {
+		ext, err := mustStapleExtension()
+		if err != nil {
+			return nil, err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+
+	derBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating x509 certificate request: %s", err.Error())
+	}
+
+	buf := &bytes.Buffer{}
+	if err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: derBytes}); err != nil {
+		return nil, fmt.Errorf("error encoding certificate request: %s", err.Error())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Issue obtains a certificate for crt from source, updating crt's Ready
+// status condition to reflect the outcome. It is the shared implementation
+// behind every issuer's top-level Issue method.
+//
+// When crt.Spec.OCSPMustStaple is set and source implements
+// OCSPStaplingSource and reports support for it, Issue also starts a
+// background OCSP staple refresh for the issued certificate against
+// crt.Spec.SecretName, using client to read and update that Secret. The
+// refresh runs for the lifetime of the process, not of this call: it is
+// owned by a single package-scoped Stapler keyed by namespace/secretName,
+// so a later renewal of the same Certificate replaces its refresh loop
+// instead of leaking another one alongside it.
+func Issue(ctx context.Context, client kubernetes.Interface, source CertificateSource, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	keyPEM, chainPEM, err := source.Obtain(ctx, crt)
+	if err != nil {
+		s := messageErrorIssueCert + err.Error()
+		crt.UpdateStatusCondition(v1alpha1.CertificateConditionReady, v1alpha1.ConditionFalse, errorIssueCert, s)
+		return nil, nil, err
+	}
+
+	crt.UpdateStatusCondition(v1alpha1.CertificateConditionReady, v1alpha1.ConditionTrue, successCertIssued, messageCertIssued)
+
+	if crt.Spec.OCSPMustStaple {
+		if staplingSource, ok := source.(OCSPStaplingSource); ok && staplingSource.SupportsOCSPStapling() {
+			scheduleOCSPStaple(client, crt, chainPEM)
+		}
+	}
+
+	return keyPEM, chainPEM, nil
+}
+
+var (
+	staplerMu sync.Mutex
+	stapler   *ocsp.Stapler
+)
+
+// scheduleOCSPStaple parses the leaf and issuer certificates out of
+// chainPEM and starts refreshing an OCSP staple for them into crt's target
+// Secret, via the single process-wide Stapler (created lazily against the
+// first client passed in). Failures are logged rather than returned, since
+// a stapling problem shouldn't fail an otherwise successful issuance.
+func scheduleOCSPStaple(client kubernetes.Interface, crt *v1alpha1.Certificate, chainPEM []byte) {
+	leaf, issuerCert, err := leafAndIssuer(chainPEM)
+	if err != nil {
+		glog.Errorf("error parsing issued chain for OCSP stapling of %s/%s: %s", crt.Namespace, crt.Spec.SecretName, err.Error())
+		return
+	}
+
+	staplerMu.Lock()
+	if stapler == nil {
+		stapler = ocsp.NewStapler(client)
+	}
+	s := stapler
+	staplerMu.Unlock()
+
+	s.ScheduleRefresh(crt.Namespace, crt.Spec.SecretName, leaf, issuerCert)
+}
+
+// leafAndIssuer parses the first two certificates (leaf, then issuer) out
+// of a PEM encoded certificate chain.
+func leafAndIssuer(chainPEM []byte) (leaf, issuerCert *x509.Certificate, err error) {
+	block, rest := pem.Decode(chainPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("no PEM certificate found in chain")
+	}
+	leaf, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing leaf certificate: %s", err.Error())
+	}
+
+	block, _ = pem.Decode(rest)
+	if block == nil {
+		return nil, nil, fmt.Errorf("chain has no issuer certificate after the leaf")
+	}
+	issuerCert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing issuer certificate: %s", err.Error())
+	}
+
+	return leaf, issuerCert, nil
+}
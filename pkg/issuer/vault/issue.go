@@ -1,12 +1,8 @@
 package vault
 
 import (
-	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
+	"crypto"
 	"fmt"
 	"path"
 	"strings"
@@ -15,57 +11,52 @@ import (
 	"github.com/golang/glog"
 	vault "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/helper/certutil"
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
-	"github.com/jetstack/cert-manager/pkg/util/errors"
-	"github.com/jetstack/cert-manager/pkg/util/kube"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/storage"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
-	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 const (
 	errorGetCertKeyPair = "ErrGetCertKeyPair"
-	errorIssueCert      = "ErrIssueCert"
-
-	successCertIssued = "CertIssueSuccess"
-
-	messageErrorIssueCert = "Error issuing TLS certificate: "
-
-	messageCertIssued = "Certificate issued successfully"
 )
 
 const (
 	// certificateDuration of 90 days if issuer.spec.duration is not set
 	certificateDuration = time.Hour * 24 * 90
-
-	defaultOrganization = "cert-manager"
-
-	keyBitSize = 2048
 )
 
+// Issue obtains a signed certificate for crt from Vault via issuer.Issue,
+// satisfying the Issue-style callers used elsewhere in the controller.
 func (v *Vault) Issue(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
-	key, certPem, err := v.obtainCertificate(ctx, crt)
-	if err != nil {
-		s := messageErrorIssueCert + err.Error()
-		crt.UpdateStatusCondition(v1alpha1.CertificateConditionReady, v1alpha1.ConditionFalse, errorIssueCert, s)
-		return nil, nil, err
-	}
-
-	crt.UpdateStatusCondition(v1alpha1.CertificateConditionReady, v1alpha1.ConditionTrue, successCertIssued, messageCertIssued)
+	return issuer.Issue(ctx, v.client, v, crt)
+}
 
-	return key, certPem, nil
+// SupportsOCSPStapling implements issuer.OCSPStaplingSource: Vault-issued
+// certificates carry a real OCSP responder URL in their AIA extension.
+func (v *Vault) SupportsOCSPStapling() bool {
+	return true
 }
 
-func (v *Vault) obtainCertificate(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+// Obtain implements issuer.CertificateSource.
+func (v *Vault) Obtain(ctx context.Context, crt *v1alpha1.Certificate) ([]byte, []byte, error) {
+	storageKey := storage.Key{Namespace: crt.Namespace, Name: crt.Spec.SecretName}
+
+	legacy := storage.NewSecretStorage(v.client)
+	if err := storage.Migrate(ctx, legacy, v.storage, storageKey); err != nil {
+		return nil, nil, fmt.Errorf("error migrating stored certificate key pair: %s", err.Error())
+	}
+
 	// get existing certificate private key
-	signeeKey, err := kube.SecretTLSKey(v.secretsLister, crt.Namespace, crt.Spec.SecretName)
-	if k8sErrors.IsNotFound(err) || errors.IsInvalidData(err) {
-		signeeKey, err = pki.GenerateRSAPrivateKey(keyBitSize)
+	signeeKey, err := v.loadPrivateKey(ctx, storageKey)
+	if storage.IsNotFound(err) {
+		signeeKey, err = issuer.GeneratePrivateKey(crt)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating private key: %s", err.Error())
 		}
-	}
-
-	if err != nil {
+	} else if err != nil {
 		return nil, nil, fmt.Errorf("error getting certificate private key: %s", err.Error())
 	}
 
@@ -80,12 +71,46 @@ func (v *Vault) obtainCertificate(ctx context.Context, crt *v1alpha1.Certificate
 		return nil, nil, err
 	}
 
-	return pki.EncodePKCS1PrivateKey(signeeKey), crtPem, nil
+	keyPem, err := pki.EncodePrivateKey(signeeKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding private key: %s", err.Error())
+	}
+
+	if err := v.storage.Store(ctx, storageKey, map[string][]byte{
+		corev1.TLSPrivateKeyKey: keyPem,
+		corev1.TLSCertKey:       crtPem,
+	}); err != nil {
+		return nil, nil, fmt.Errorf("error storing issued certificate key pair: %s", err.Error())
+	}
+
+	return keyPem, crtPem, nil
+}
+
+// loadPrivateKey returns the private key stored for key in v's configured
+// storage backend, or an error satisfying storage.IsNotFound if no entry
+// exists or it has no usable private key.
+func (v *Vault) loadPrivateKey(ctx context.Context, key storage.Key) (crypto.Signer, error) {
+	data, err := v.storage.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, ok := data[corev1.TLSPrivateKeyKey]
+	if !ok {
+		return nil, storage.NotFound(key)
+	}
+
+	signer, err := pki.DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		return nil, storage.NotFound(key)
+	}
+
+	return signer, nil
 }
 
 // signCertificate returns a signed x509.Certificate object for the given
 // *v1alpha1.Certificate crt.
-func (v *Vault) signCertificate(crt *v1alpha1.Certificate, key *rsa.PrivateKey) ([]byte, error) {
+func (v *Vault) signCertificate(crt *v1alpha1.Certificate, key crypto.Signer) ([]byte, error) {
 	commonName, err := pki.CommonNameForCertificate(crt)
 	if err != nil {
 		return nil, fmt.Errorf("error extracting Common Name from certificate: %s", err.Error())
@@ -100,21 +125,12 @@ func (v *Vault) signCertificate(crt *v1alpha1.Certificate, key *rsa.PrivateKey)
 		commonName = altNames[0]
 	}
 
-	template := pki.GenerateCSR(commonName, altNames...)
-	template.Subject.Organization = []string{defaultOrganization}
-
-	derBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
-	if err != nil {
-		return nil, fmt.Errorf("error creating x509 certificate: %s", err.Error())
-	}
-
-	pemRequestBuf := &bytes.Buffer{}
-	err = pem.Encode(pemRequestBuf, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: derBytes})
+	csrPEM, err := issuer.BuildCSR(crt, key)
 	if err != nil {
-		return nil, fmt.Errorf("error encoding certificate request: %s", err.Error())
+		return nil, err
 	}
 
-	return v.requestVaultCert(commonName, altNames, pemRequestBuf.String())
+	return v.requestVaultCert(commonName, altNames, string(csrPEM))
 }
 
 func (v *Vault) initVaultClient() (*vault.Client, error) {
@@ -147,7 +163,18 @@ func (v *Vault) initVaultClient() (*vault.Client, error) {
 		return client, nil
 	}
 
-	return nil, fmt.Errorf("error initializing Vault client. tokenSecretRef or appRoleSecretRef not set")
+	kubernetesAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
+	if kubernetesAuth.Role != "" {
+		token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+		if err != nil {
+			return nil, fmt.Errorf("error logging in to Vault via Kubernetes auth: %s", err.Error())
+		}
+		client.SetToken(token)
+
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("error initializing Vault client. tokenSecretRef, appRoleSecretRef or kubernetes auth not set")
 }
 
 func (v *Vault) requestTokenWithAppRoleRef(client *vault.Client, appRoleRef string) (string, error) {
@@ -197,6 +224,24 @@ func (v *Vault) requestVaultCert(commonName string, altNames []string, csr strin
 		return nil, err
 	}
 
+	bundlePEM, err := v.doRequestVaultCert(client, commonName, altNames, csr)
+	if isForbidden(err) {
+		// The Kubernetes auth method issues short-lived tokens; a 403 here
+		// most likely means ours has expired, so log in again once before
+		// giving up.
+		glog.V(4).Infof("Vault request forbidden, retrying after fresh login")
+		client, err = v.initVaultClient()
+		if err != nil {
+			return nil, err
+		}
+		bundlePEM, err = v.doRequestVaultCert(client, commonName, altNames, csr)
+	}
+
+	return bundlePEM, err
+}
+
+func (v *Vault) doRequestVaultCert(client *vault.Client, commonName string, altNames []string, csr string) ([]byte, error) {
+	var err error
 	glog.V(4).Infof("Vault certificate request for commonName %s altNames: %q", commonName, altNames)
 
 	certDuration := certificateDuration
@@ -205,10 +250,10 @@ func (v *Vault) requestVaultCert(commonName string, altNames []string, csr strin
 	}
 
 	parameters := map[string]string{
-		"common_name": commonName,
-		"alt_names":   strings.Join(altNames, ","),
-		"ttl":         certDuration.String(),
-		"csr":         csr,
+		"common_name":          commonName,
+		"alt_names":            strings.Join(altNames, ","),
+		"ttl":                  certDuration.String(),
+		"csr":                  csr,
 		"exclude_cn_from_sans": "true",
 	}
 
@@ -0,0 +1,116 @@
+package vault
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+// defaultServiceAccountTokenPath is where the kubelet projects the pod's
+// ServiceAccount token by default.
+const defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// defaultKubernetesAuthMountPath is Vault's default mount path for the
+// kubernetes auth method.
+const defaultKubernetesAuthMountPath = "kubernetes"
+
+// requestTokenWithKubernetesAuth logs in to Vault's Kubernetes auth method
+// using the pod's projected ServiceAccount token as the JWT, returning the
+// client token Vault issues in response.
+func (v *Vault) requestTokenWithKubernetesAuth(client *vault.Client, auth v1alpha1.VaultKubernetesAuth) (string, error) {
+	jwt, err := v.serviceAccountToken(auth)
+	if err != nil {
+		return "", fmt.Errorf("error reading ServiceAccount token: %s", err.Error())
+	}
+
+	mountPath := auth.MountPath
+	if mountPath == "" {
+		mountPath = defaultKubernetesAuthMountPath
+	}
+
+	parameters := map[string]string{
+		"role": auth.Role,
+		"jwt":  jwt,
+	}
+
+	url := path.Join("/v1/auth", mountPath, "login")
+
+	request := client.NewRequest("POST", url)
+
+	if err := request.SetJSONBody(parameters); err != nil {
+		return "", fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+	}
+
+	resp, err := client.RawRequest(request)
+	if err != nil {
+		return "", fmt.Errorf("error calling Vault server: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	vaultResult := vault.Secret{}
+	if err := resp.DecodeJSON(&vaultResult); err != nil {
+		return "", fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+	}
+
+	token, err := vaultResult.TokenID()
+	if err != nil {
+		return "", fmt.Errorf("unable to read token: %s", err.Error())
+	}
+
+	return token, nil
+}
+
+// serviceAccountToken reads the pod's ServiceAccount token either from a
+// referenced Secret (for out-of-cluster testing) or from the projected
+// token path on disk, which defaults to the standard kubelet location.
+func (v *Vault) serviceAccountToken(auth v1alpha1.VaultKubernetesAuth) (string, error) {
+	if auth.SecretRef.Name != "" {
+		secret, err := v.secretsLister.Secrets(v.issuerResourcesNamespace).Get(auth.SecretRef.Name)
+		if err != nil {
+			return "", err
+		}
+
+		key := auth.SecretRef.Key
+		if key == "" {
+			key = "token"
+		}
+
+		token, ok := secret.Data[key]
+		if !ok {
+			return "", fmt.Errorf("no data for %q in secret '%s/%s'", key, v.issuerResourcesNamespace, auth.SecretRef.Name)
+		}
+
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	tokenPath := auth.TokenPath
+	if tokenPath == "" {
+		tokenPath = defaultServiceAccountTokenPath
+	}
+
+	token, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// isForbidden reports whether err represents a 403 response from Vault,
+// which for the Kubernetes auth method typically means the cached client
+// token has expired and a fresh login is required.
+func isForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	respErr, ok := err.(*vault.ResponseError)
+	if !ok {
+		return false
+	}
+	return respErr.StatusCode == 403
+}
@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+func TestRequestTokenWithKubernetesAuth(t *testing.T) {
+	const wantRole = "cert-manager"
+	const wantJWT = "fake-service-account-jwt"
+	const wantToken = "fake-vault-client-token"
+
+	tokenFile, err := ioutil.TempFile("", "sa-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tokenFile.Close()
+	if _, err := tokenFile.WriteString(wantJWT + "\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/kubernetes/login" {
+			t.Errorf("unexpected login path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body struct {
+			Role string `json:"role"`
+			JWT  string `json:"jwt"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Role != wantRole {
+			t.Errorf("unexpected role: got %q, want %q", body.Role, wantRole)
+		}
+		if body.JWT != wantJWT {
+			t.Errorf("unexpected jwt: got %q, want %q", body.JWT, wantJWT)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token": wantToken,
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetAddress(server.URL)
+
+	v := &Vault{}
+	token, err := v.requestTokenWithKubernetesAuth(client, v1alpha1.VaultKubernetesAuth{
+		Role:      wantRole,
+		TokenPath: tokenFile.Name(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if token != wantToken {
+		t.Errorf("unexpected token: got %q, want %q", token, wantToken)
+	}
+}
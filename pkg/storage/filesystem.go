@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Filesystem is a storage.Interface that persists entries as files on disk,
+// one directory per Key and one file per data key within it. It is
+// intended for standalone (non-Kubernetes) use of cert-manager's issuer
+// packages, where there is no apiserver to store Secrets against.
+type Filesystem struct {
+	// RootDir is the directory under which every entry is stored, as
+	// RootDir/namespace/name/<data key>.
+	RootDir string
+}
+
+// NewFilesystem returns a Filesystem backend rooted at rootDir.
+func NewFilesystem(rootDir string) *Filesystem {
+	return &Filesystem{RootDir: rootDir}
+}
+
+func (f *Filesystem) dir(key Key) string {
+	return filepath.Join(f.RootDir, key.Namespace, key.Name)
+}
+
+// Load implements Interface.
+func (f *Filesystem) Load(ctx context.Context, key Key) (map[string][]byte, error) {
+	dir := f.dir(key)
+
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, NotFound(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		data[entry.Name()] = contents
+	}
+
+	return data, nil
+}
+
+// Store implements Interface.
+func (f *Filesystem) Store(ctx context.Context, key Key, data map[string][]byte) error {
+	dir := f.dir(key)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for name, contents := range data {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), contents, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete implements Interface.
+func (f *Filesystem) Delete(ctx context.Context, key Key) error {
+	err := os.RemoveAll(f.dir(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// List implements Interface.
+func (f *Filesystem) List(ctx context.Context, namespace string) ([]Key, error) {
+	nsDir := filepath.Join(f.RootDir, namespace)
+
+	entries, err := ioutil.ReadDir(nsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		keys = append(keys, Key{Namespace: namespace, Name: entry.Name()})
+	}
+
+	return keys, nil
+}
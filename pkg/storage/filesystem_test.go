@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFilesystemStoreLoadDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cert-manager-storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fs := NewFilesystem(dir)
+	ctx := context.Background()
+	key := Key{Namespace: "default", Name: "example-com"}
+
+	if _, err := fs.Load(ctx, key); !IsNotFound(err) {
+		t.Fatalf("expected IsNotFound before Store, got %v", err)
+	}
+
+	want := map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")}
+	if err := fs.Store(ctx, key, want); err != nil {
+		t.Fatalf("unexpected error from Store: %s", err)
+	}
+
+	got, err := fs.Load(ctx, key)
+	if err != nil {
+		t.Fatalf("unexpected error from Load: %s", err)
+	}
+	for k, v := range want {
+		if string(got[k]) != string(v) {
+			t.Errorf("entry %q: got %q, want %q", k, got[k], v)
+		}
+	}
+
+	keys, err := fs.List(ctx, key.Namespace)
+	if err != nil {
+		t.Fatalf("unexpected error from List: %s", err)
+	}
+	if len(keys) != 1 || keys[0] != key {
+		t.Errorf("unexpected List result: %+v", keys)
+	}
+
+	if err := fs.Delete(ctx, key); err != nil {
+		t.Fatalf("unexpected error from Delete: %s", err)
+	}
+	if _, err := fs.Load(ctx, key); !IsNotFound(err) {
+		t.Fatalf("expected IsNotFound after Delete, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// Migrate transparently moves an entry stored under the legacy SecretStorage
+// backend into dst, the backend configured for the owning Issuer. It is
+// meant to be called on every reconcile before a Load against dst: if dst
+// already has the entry this is a no-op, and if neither backend has it the
+// caller sees the usual IsNotFound error from dst.
+//
+// This lets operators switch an Issuer's storage backend without a manual
+// export/import step, at the cost of one extra Load against the legacy
+// Secret on every reconcile until the migration has happened.
+func Migrate(ctx context.Context, legacy *SecretStorage, dst Interface, key Key) error {
+	if _, isSecretStorage := dst.(*SecretStorage); isSecretStorage {
+		// Nothing to migrate: the legacy backend is also the configured one.
+		return nil
+	}
+
+	if _, err := dst.Load(ctx, key); err == nil {
+		return nil
+	} else if !IsNotFound(err) {
+		return fmt.Errorf("error checking %s/%s in configured storage backend: %s", key.Namespace, key.Name, err.Error())
+	}
+
+	data, err := legacy.Load(ctx, key)
+	if IsNotFound(err) {
+		// No legacy data either; nothing to migrate, caller's subsequent
+		// Load against dst will report IsNotFound as usual.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading legacy secret for %s/%s: %s", key.Namespace, key.Name, err.Error())
+	}
+
+	if err := dst.Store(ctx, key, data); err != nil {
+		return fmt.Errorf("error migrating %s/%s to configured storage backend: %s", key.Namespace, key.Name, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretStorage is the default Interface implementation, storing entries as
+// corev1.Secret resources. This is the storage backend cert-manager has
+// always used, kept as the default so that existing Issuers need no
+// configuration change.
+type SecretStorage struct {
+	client kubernetes.Interface
+}
+
+// NewSecretStorage returns a SecretStorage backed by the given clientset.
+func NewSecretStorage(client kubernetes.Interface) *SecretStorage {
+	return &SecretStorage{client: client}
+}
+
+// Load implements Interface.
+func (s *SecretStorage) Load(ctx context.Context, key Key) (map[string][]byte, error) {
+	secret, err := s.client.CoreV1().Secrets(key.Namespace).Get(key.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil, NotFound(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.Data, nil
+}
+
+// Store implements Interface. It merges data into the Secret's existing
+// contents rather than replacing them outright, so that a key written by
+// one caller (e.g. the OCSP stapler's tls.ocsp-staple) survives a later
+// Store call from another caller (e.g. a certificate renewal) against the
+// same Secret.
+func (s *SecretStorage) Store(ctx context.Context, key Key, data map[string][]byte) error {
+	secrets := s.client.CoreV1().Secrets(key.Namespace)
+
+	existing, err := secrets.Get(key.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       data,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Data == nil {
+		existing.Data = map[string][]byte{}
+	}
+	for k, v := range data {
+		existing.Data[k] = v
+	}
+
+	_, err = secrets.Update(existing)
+	return err
+}
+
+// Delete implements Interface.
+func (s *SecretStorage) Delete(ctx context.Context, key Key) error {
+	err := s.client.CoreV1().Secrets(key.Namespace).Delete(key.Name, nil)
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// List implements Interface.
+func (s *SecretStorage) List(ctx context.Context, namespace string) ([]Key, error) {
+	list, err := s.client.CoreV1().Secrets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets in %q: %s", namespace, err.Error())
+	}
+
+	keys := make([]Key, 0, len(list.Items))
+	for _, secret := range list.Items {
+		keys = append(keys, Key{Namespace: secret.Namespace, Name: secret.Name})
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,56 @@
+// Package storage defines a pluggable backend for persisting the key
+// material cert-manager manages: issued certificate key pairs and ACME
+// account private keys. The default backend stores everything in
+// corev1.Secret resources, matching cert-manager's historical behaviour,
+// but operators can configure an Issuer to use an alternative backend
+// instead. The Vault issuer persists issued certificate key pairs through
+// this package; the ACME issuer in pkg/issuer/acme does not use it for
+// either its issued key pairs or its account private key.
+package storage
+
+import "context"
+
+// Interface is implemented by every storage backend. Entries are addressed
+// by a namespace/name key, mirroring the Kubernetes object they logically
+// belong to, and store an opaque set of named values (e.g. "tls.crt",
+// "tls.key").
+type Interface interface {
+	// Load returns the stored values for key, or an error satisfying
+	// IsNotFound if no entry exists.
+	Load(ctx context.Context, key Key) (map[string][]byte, error)
+
+	// Store creates or updates the entry for key with data.
+	Store(ctx context.Context, key Key, data map[string][]byte) error
+
+	// Delete removes the entry for key, if any.
+	Delete(ctx context.Context, key Key) error
+
+	// List returns the keys of every entry in namespace.
+	List(ctx context.Context, namespace string) ([]Key, error)
+}
+
+// Key addresses a single entry in a storage backend.
+type Key struct {
+	Namespace string
+	Name      string
+}
+
+type notFoundError struct {
+	key Key
+}
+
+func (e *notFoundError) Error() string {
+	return "no entry found for " + e.key.Namespace + "/" + e.key.Name
+}
+
+// NotFound returns an error satisfying IsNotFound for key.
+func NotFound(key Key) error {
+	return &notFoundError{key: key}
+}
+
+// IsNotFound reports whether err indicates that a Load found no entry for
+// the requested key.
+func IsNotFound(err error) bool {
+	_, ok := err.(*notFoundError)
+	return ok
+}
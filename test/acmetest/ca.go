@@ -0,0 +1,394 @@
+// Package acmetest provides an in-process ACME test CA exercised by this
+// package's own tests (see ca_test.go). It is modelled on the acmetest
+// harness x/crypto carries for its own ACME client tests: a
+// httptest.Server speaking just enough of the ACME directory/new-nonce/
+// new-account/new-order/finalize flow to drive a single-domain issuance,
+// verifying HTTP-01 or TLS-ALPN-01 challenges against a caller-supplied
+// Resolver.
+//
+// This CA is not yet wired into test/e2e/framework or any test of the
+// real ACME issuer (pkg/issuer/acme.ACME): the e2e specs that exercise
+// ACME issuance still run against the external server configured via
+// framework.TestContext.ACMEURL. It also doesn't verify the signed JWS
+// envelope every request from golang.org/x/crypto/acme.Client carries —
+// its handlers decode plain JSON bodies — so it isn't a drop-in
+// replacement for that external server yet; ca_test.go drives it with
+// plain http.Post calls rather than the real client for the same reason.
+package acmetest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Resolver is implemented by the caller to answer whether a challenge for
+// domain has been correctly provisioned. The CA calls it when a client
+// requests validation of a challenge, so tests can drive the real issuer
+// controller's Present/CleanUp logic and have the CA observe the result.
+type Resolver interface {
+	// ResolveHTTP01 is called to validate an http-01 challenge: it should
+	// return true if domain currently serves token/keyAuthorization at
+	// /.well-known/acme-challenge/<token>.
+	ResolveHTTP01(domain, token, keyAuthorization string) (bool, error)
+
+	// ResolveTLSALPN01 is called to validate a tls-alpn-01 challenge: it
+	// should return true if domain presents a valid challenge certificate
+	// for keyAuthorization when offered the acme-tls/1 ALPN protocol.
+	ResolveTLSALPN01(domain, keyAuthorization string) (bool, error)
+}
+
+// FailureMode lets a test inject a specific failure into the next matching
+// request the CA receives.
+type FailureMode int
+
+const (
+	// FailNone performs no injected failure.
+	FailNone FailureMode = iota
+	// FailBadNonce causes the next new-account request to be rejected
+	// with a badNonce problem, exercising the client's nonce-retry logic.
+	FailBadNonce
+	// FailRateLimited causes the next new-order request to be rejected
+	// with a rateLimited problem.
+	FailRateLimited
+	// FailPendingForever causes the authorization of the next new-order
+	// request to never leave the "pending" state, exercising polling
+	// timeouts.
+	FailPendingForever
+)
+
+type authz struct {
+	domain      string
+	status      string
+	pendingOnly bool
+}
+
+// CA is an in-process ACME test server.
+type CA struct {
+	Server   *httptest.Server
+	Resolver Resolver
+
+	key *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	nonces map[string]bool
+	authzs map[string]*authz
+	orders map[string]string // order ID -> authz ID
+	fail   FailureMode
+	certs  map[string][]byte // order ID -> issued cert DER
+	accts  int
+}
+
+// NewCA starts a new in-process ACME test CA. The caller must set Resolver
+// before issuing any orders.
+func NewCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating test CA key: %s", err.Error())
+	}
+
+	ca := &CA{
+		key:    key,
+		nonces: map[string]bool{},
+		authzs: map[string]*authz{},
+		orders: map[string]string{},
+		certs:  map[string][]byte{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/directory", ca.handleDirectory)
+	mux.HandleFunc("/new-nonce", ca.handleNewNonce)
+	mux.HandleFunc("/new-account", ca.handleNewAccount)
+	mux.HandleFunc("/new-order", ca.handleNewOrder)
+	mux.HandleFunc("/authz/", ca.handleAuthz)
+	mux.HandleFunc("/challenge/", ca.handleChallenge)
+	mux.HandleFunc("/finalize/", ca.handleFinalize)
+	mux.HandleFunc("/cert/", ca.handleCert)
+
+	ca.Server = httptest.NewServer(mux)
+
+	return ca, nil
+}
+
+// InjectFailure arms a one-shot failure of the given mode for the next
+// matching request.
+func (ca *CA) InjectFailure(mode FailureMode) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.fail = mode
+}
+
+func (ca *CA) takeFailure(want FailureMode) bool {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if ca.fail == want {
+		ca.fail = FailNone
+		return true
+	}
+	return false
+}
+
+// Close shuts down the underlying httptest.Server.
+func (ca *CA) Close() {
+	ca.Server.Close()
+}
+
+// URL returns the CA's ACME directory URL.
+func (ca *CA) URL() string {
+	return ca.Server.URL + "/directory"
+}
+
+func (ca *CA) newNonce() string {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	nonce := fmt.Sprintf("nonce-%d", len(ca.nonces)+1)
+	ca.nonces[nonce] = true
+	return nonce
+}
+
+func (ca *CA) writeNonce(w http.ResponseWriter) {
+	w.Header().Set("Replay-Nonce", ca.newNonce())
+}
+
+func writeProblem(w http.ResponseWriter, status int, problemType, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"type":   "urn:ietf:params:acme:error:" + problemType,
+		"detail": detail,
+	})
+}
+
+func (ca *CA) handleDirectory(w http.ResponseWriter, r *http.Request) {
+	ca.writeNonce(w)
+	json.NewEncoder(w).Encode(map[string]string{
+		"newNonce":   ca.Server.URL + "/new-nonce",
+		"newAccount": ca.Server.URL + "/new-account",
+		"newOrder":   ca.Server.URL + "/new-order",
+	})
+}
+
+func (ca *CA) handleNewNonce(w http.ResponseWriter, r *http.Request) {
+	ca.writeNonce(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ca *CA) handleNewAccount(w http.ResponseWriter, r *http.Request) {
+	if ca.takeFailure(FailBadNonce) {
+		writeProblem(w, http.StatusBadRequest, "badNonce", "injected bad nonce for test")
+		return
+	}
+
+	ca.mu.Lock()
+	ca.accts++
+	id := ca.accts
+	ca.mu.Unlock()
+
+	ca.writeNonce(w)
+	w.Header().Set("Location", fmt.Sprintf("%s/account/%d", ca.Server.URL, id))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"status": "valid"})
+}
+
+type newOrderRequest struct {
+	Identifiers []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifiers"`
+}
+
+func (ca *CA) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	if ca.takeFailure(FailRateLimited) {
+		writeProblem(w, http.StatusTooManyRequests, "rateLimited", "injected rate limit for test")
+		return
+	}
+
+	var req newOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Identifiers) == 0 {
+		writeProblem(w, http.StatusBadRequest, "malformed", "could not parse order identifiers")
+		return
+	}
+	domain := req.Identifiers[0].Value
+	pendingOnly := ca.takeFailure(FailPendingForever)
+
+	ca.mu.Lock()
+	orderID := fmt.Sprintf("%d", len(ca.orders)+1)
+	authzID := fmt.Sprintf("%d", len(ca.authzs)+1)
+	ca.authzs[authzID] = &authz{domain: domain, status: "pending", pendingOnly: pendingOnly}
+	ca.orders[orderID] = authzID
+	ca.mu.Unlock()
+
+	ca.writeNonce(w)
+	w.Header().Set("Location", fmt.Sprintf("%s/order/%s", ca.Server.URL, orderID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         "pending",
+		"authorizations": []string{fmt.Sprintf("%s/authz/%s", ca.Server.URL, authzID)},
+		"finalize":       fmt.Sprintf("%s/finalize/%s", ca.Server.URL, orderID),
+	})
+}
+
+func (ca *CA) handleAuthz(w http.ResponseWriter, r *http.Request) {
+	id := lastPathSegment(r.URL.Path)
+
+	ca.mu.Lock()
+	a, ok := ca.authzs[id]
+	ca.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	ca.writeNonce(w)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     a.status,
+		"identifier": map[string]string{"type": "dns", "value": a.domain},
+		"challenges": []map[string]string{
+			{"type": "http-01", "url": fmt.Sprintf("%s/challenge/%s/http-01", ca.Server.URL, id), "token": "test-token-" + id},
+			{"type": "tls-alpn-01", "url": fmt.Sprintf("%s/challenge/%s/tls-alpn-01", ca.Server.URL, id), "token": "test-token-" + id},
+		},
+	})
+}
+
+func (ca *CA) handleChallenge(w http.ResponseWriter, r *http.Request) {
+	if ca.Resolver == nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", "no Resolver configured on test CA")
+		return
+	}
+
+	// path shape: /challenge/<authzID>/<type>
+	parts := splitPath(r.URL.Path)
+	if len(parts) != 3 {
+		http.NotFound(w, r)
+		return
+	}
+	authzID, challengeType := parts[1], parts[2]
+
+	ca.mu.Lock()
+	a, ok := ca.authzs[authzID]
+	ca.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if a.pendingOnly {
+		ca.writeNonce(w)
+		json.NewEncoder(w).Encode(map[string]string{"status": "pending"})
+		return
+	}
+
+	keyAuth := "test-token-" + authzID + ".thumbprint"
+
+	var valid bool
+	var err error
+	switch challengeType {
+	case "http-01":
+		valid, err = ca.Resolver.ResolveHTTP01(a.domain, "test-token-"+authzID, keyAuth)
+	case "tls-alpn-01":
+		valid, err = ca.Resolver.ResolveTLSALPN01(a.domain, keyAuth)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil || !valid {
+		ca.mu.Lock()
+		a.status = "invalid"
+		ca.mu.Unlock()
+		writeProblem(w, http.StatusOK, "incorrectResponse", "challenge validation failed")
+		return
+	}
+
+	ca.mu.Lock()
+	a.status = "valid"
+	ca.mu.Unlock()
+
+	ca.writeNonce(w)
+	json.NewEncoder(w).Encode(map[string]string{"status": "valid"})
+}
+
+func (ca *CA) handleFinalize(w http.ResponseWriter, r *http.Request) {
+	orderID := lastPathSegment(r.URL.Path)
+
+	ca.mu.Lock()
+	authzID, ok := ca.orders[orderID]
+	ca.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	ca.mu.Lock()
+	a := ca.authzs[authzID]
+	ca.mu.Unlock()
+	if a == nil || a.status != "valid" {
+		writeProblem(w, http.StatusForbidden, "orderNotReady", "authorization is not valid")
+		return
+	}
+
+	certDER, err := ca.signTestCertificate(a.domain)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	ca.mu.Lock()
+	ca.certs[orderID] = certDER
+	ca.mu.Unlock()
+
+	ca.writeNonce(w)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":      "valid",
+		"certificate": fmt.Sprintf("%s/cert/%s", ca.Server.URL, orderID),
+	})
+}
+
+func (ca *CA) handleCert(w http.ResponseWriter, r *http.Request) {
+	orderID := lastPathSegment(r.URL.Path)
+
+	ca.mu.Lock()
+	der, ok := ca.certs[orderID]
+	ca.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func (ca *CA) signTestCertificate(domain string) ([]byte, error) {
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: bigOne(),
+		Subject:      pkixName(domain),
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          bigOne(),
+		Subject:               pkixName("Test CA"),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	return x509.CreateCertificate(rand.Reader, template, caTemplate, &leafKey.PublicKey, ca.key)
+}
@@ -0,0 +1,183 @@
+package acmetest
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+type fakeResolver struct {
+	http01Valid    bool
+	tlsalpn01Valid bool
+}
+
+func (f *fakeResolver) ResolveHTTP01(domain, token, keyAuthorization string) (bool, error) {
+	return f.http01Valid, nil
+}
+
+func (f *fakeResolver) ResolveTLSALPN01(domain, keyAuthorization string) (bool, error) {
+	return f.tlsalpn01Valid, nil
+}
+
+// TestCAIssuesWhenChallengeResolves drives the full new-account -> new-order
+// -> challenge -> finalize -> certificate flow against the test CA, and
+// checks that a certificate is only issued when the configured Resolver
+// reports the challenge as resolved.
+func TestCAIssuesWhenChallengeResolves(t *testing.T) {
+	const domain = "example.com"
+
+	cases := []struct {
+		name     string
+		resolver *fakeResolver
+		wantErr  bool
+	}{
+		{"http-01 resolves", &fakeResolver{http01Valid: true}, false},
+		{"http-01 fails to resolve", &fakeResolver{http01Valid: false}, true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ca, err := NewCA()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ca.Close()
+			ca.Resolver = c.resolver
+
+			if resp, err := http.Post(ca.Server.URL+"/new-account", "application/jose+json", nil); err != nil {
+				t.Fatalf("new-account: %s", err.Error())
+			} else {
+				resp.Body.Close()
+			}
+
+			orderBody, err := json.Marshal(map[string]interface{}{
+				"identifiers": []map[string]string{{"type": "dns", "value": domain}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			orderResp, err := http.Post(ca.Server.URL+"/new-order", "application/jose+json", bytes.NewReader(orderBody))
+			if err != nil {
+				t.Fatalf("new-order: %s", err.Error())
+			}
+			defer orderResp.Body.Close()
+
+			var order struct {
+				Authorizations []string `json:"authorizations"`
+				Finalize       string   `json:"finalize"`
+			}
+			if err := json.NewDecoder(orderResp.Body).Decode(&order); err != nil {
+				t.Fatalf("decoding order: %s", err.Error())
+			}
+			if len(order.Authorizations) == 0 {
+				t.Fatal("order has no authorizations")
+			}
+
+			authzResp, err := http.Get(order.Authorizations[0])
+			if err != nil {
+				t.Fatalf("get authorization: %s", err.Error())
+			}
+			defer authzResp.Body.Close()
+
+			var authzDoc struct {
+				Challenges []struct {
+					Type string `json:"type"`
+					URL  string `json:"url"`
+				} `json:"challenges"`
+			}
+			if err := json.NewDecoder(authzResp.Body).Decode(&authzDoc); err != nil {
+				t.Fatalf("decoding authorization: %s", err.Error())
+			}
+
+			var challengeURL string
+			for _, ch := range authzDoc.Challenges {
+				if ch.Type == "http-01" {
+					challengeURL = ch.URL
+				}
+			}
+			if challengeURL == "" {
+				t.Fatal("no http-01 challenge offered")
+			}
+
+			if resp, err := http.Post(challengeURL, "application/jose+json", nil); err != nil {
+				t.Fatalf("post challenge: %s", err.Error())
+			} else {
+				resp.Body.Close()
+			}
+
+			finalizeResp, err := http.Post(order.Finalize, "application/jose+json", nil)
+			if err != nil {
+				t.Fatalf("finalize: %s", err.Error())
+			}
+			defer finalizeResp.Body.Close()
+
+			if c.wantErr {
+				if finalizeResp.StatusCode == http.StatusOK {
+					t.Fatalf("expected finalize to fail when the challenge does not resolve, got status %d", finalizeResp.StatusCode)
+				}
+				return
+			}
+
+			if finalizeResp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected finalize status: %d", finalizeResp.StatusCode)
+			}
+
+			var final struct {
+				Certificate string `json:"certificate"`
+			}
+			if err := json.NewDecoder(finalizeResp.Body).Decode(&final); err != nil {
+				t.Fatalf("decoding finalize response: %s", err.Error())
+			}
+
+			certResp, err := http.Get(final.Certificate)
+			if err != nil {
+				t.Fatalf("get certificate: %s", err.Error())
+			}
+			defer certResp.Body.Close()
+
+			certPEM, err := ioutil.ReadAll(certResp.Body)
+			if err != nil {
+				t.Fatalf("reading certificate: %s", err.Error())
+			}
+
+			block, _ := pem.Decode(certPEM)
+			if block == nil {
+				t.Fatal("no PEM certificate returned")
+			}
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("parsing issued certificate: %s", err.Error())
+			}
+			if len(cert.DNSNames) != 1 || cert.DNSNames[0] != domain {
+				t.Fatalf("issued certificate has DNSNames %v, want [%s]", cert.DNSNames, domain)
+			}
+		})
+	}
+}
+
+func TestCAInjectsBadNonce(t *testing.T) {
+	ca, err := NewCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ca.Close()
+
+	ca.InjectFailure(FailBadNonce)
+
+	resp, err := http.Post(ca.Server.URL+"/new-account", "application/jose+json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected injected badNonce to produce a 400, got %d", resp.StatusCode)
+	}
+}
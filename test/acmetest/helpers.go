@@ -0,0 +1,37 @@
+package acmetest
+
+import (
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+)
+
+// splitPath splits a request path such as "/challenge/1/http-01" into its
+// non-empty segments, e.g. ["challenge", "1", "http-01"].
+func splitPath(p string) []string {
+	parts := strings.Split(p, "/")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// lastPathSegment returns the final non-empty segment of p.
+func lastPathSegment(p string) string {
+	parts := splitPath(p)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+func bigOne() *big.Int {
+	return big.NewInt(1)
+}
+
+func pkixName(commonName string) pkix.Name {
+	return pkix.Name{CommonName: commonName}
+}
@@ -105,6 +105,36 @@ var _ = framework.CertManagerDescribe("ACME Certificate (HTTP01)", func() {
 		f.WaitCertificateIssuedValid(cert)
 	})
 
+	ecdsaCases := []struct {
+		keySize int
+		label   string
+	}{
+		{256, "should obtain a signed ECDSA P-256 certificate from the ACME server"},
+		{384, "should obtain a signed ECDSA P-384 certificate from the ACME server"},
+	}
+
+	for _, v := range ecdsaCases {
+		v := v
+		It(v.label, func() {
+			certificateName := fmt.Sprintf("test-acme-ecdsa-%d-certificate", v.keySize)
+			certificateSecretName := certificateName
+
+			By("Creating a Certificate requesting an ECDSA key")
+			crt := util.NewCertManagerACMECertificate(certificateName, certificateSecretName, issuerName, v1alpha1.IssuerKind, acmeIngressClass, util.ACMECertificateDomain)
+			crt.Spec.KeyAlgorithm = v1alpha1.ECDSAKeyAlgorithm
+			crt.Spec.KeySize = v.keySize
+
+			cert, err := f.CertManagerClientSet.CertmanagerV1alpha1().Certificates(f.Namespace.Name).Create(crt)
+			Expect(err).NotTo(HaveOccurred())
+			f.WaitCertificateIssuedValid(cert)
+
+			By("Verifying the issued tls.key is an ECDSA key")
+			secret, err := f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Get(certificateSecretName, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(util.KeyIsECDSA(secret.Data[corev1.TLSPrivateKeyKey])).To(BeTrue())
+		})
+	}
+
 	It("should fail to obtain a certificate for an invalid ACME dns name", func() {
 		By("Creating a Certificate")
 		_, err := f.CertManagerClientSet.CertmanagerV1alpha1().Certificates(f.Namespace.Name).Create(util.NewCertManagerACMECertificate(certificateName, certificateSecretName, issuerName, v1alpha1.IssuerKind, acmeIngressClass, "google.com"))
@@ -0,0 +1,76 @@
+/*
+Copyright 2017 Jetstack Ltd.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	cmutil "github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/test/e2e/framework"
+	"github.com/jetstack/cert-manager/test/util"
+)
+
+const testingACMETLSALPNPrivateKey = "test-acme-tls-alpn-private-key"
+
+var _ = framework.CertManagerDescribe("ACME Certificate (TLS-ALPN01)", func() {
+	f := framework.NewDefaultFramework("create-acme-certificate-tlsalpn01")
+
+	issuerName := "test-acme-tlsalpn-issuer"
+	certificateName := "test-acme-tlsalpn-certificate"
+	certificateSecretName := "test-acme-tlsalpn-certificate"
+
+	BeforeEach(func() {
+		By("Verifying there is no existing ACME private key")
+		_, err := f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Get(testingACMETLSALPNPrivateKey, metav1.GetOptions{})
+		Expect(err).To(MatchError(apierrors.NewNotFound(corev1.Resource("secrets"), testingACMETLSALPNPrivateKey)))
+		By("Creating an Issuer")
+		_, err = f.CertManagerClientSet.CertmanagerV1alpha1().Issuers(f.Namespace.Name).Create(util.NewCertManagerACMEIssuer(issuerName, framework.TestContext.ACMEURL, testingACMEEmail, testingACMETLSALPNPrivateKey, 0, 0))
+		Expect(err).NotTo(HaveOccurred())
+		By("Waiting for Issuer to become Ready")
+		err = util.WaitForIssuerCondition(f.CertManagerClientSet.CertmanagerV1alpha1().Issuers(f.Namespace.Name),
+			issuerName,
+			v1alpha1.IssuerCondition{
+				Type:   v1alpha1.IssuerConditionReady,
+				Status: v1alpha1.ConditionTrue,
+			})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		By("Cleaning up")
+		f.CertManagerClientSet.CertmanagerV1alpha1().Issuers(f.Namespace.Name).Delete(issuerName, nil)
+		f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Delete(testingACMETLSALPNPrivateKey, nil)
+	})
+
+	It("should obtain a signed certificate for a single domain using TLS-ALPN-01", func() {
+		By("Creating a Certificate")
+		cert, err := f.CertManagerClientSet.CertmanagerV1alpha1().Certificates(f.Namespace.Name).Create(util.NewCertManagerACMETLSALPNCertificate(certificateName, certificateSecretName, issuerName, v1alpha1.IssuerKind, util.ACMECertificateDomain))
+		Expect(err).NotTo(HaveOccurred())
+		f.WaitCertificateIssuedValid(cert)
+	})
+
+	It("should obtain a signed certificate with a CN and subdomain using TLS-ALPN-01", func() {
+		By("Creating a Certificate")
+		cert, err := f.CertManagerClientSet.CertmanagerV1alpha1().Certificates(f.Namespace.Name).Create(util.NewCertManagerACMETLSALPNCertificate(certificateName, certificateSecretName, issuerName, v1alpha1.IssuerKind, util.ACMECertificateDomain, fmt.Sprintf("%s.%s", cmutil.RandStringRunes(5), util.ACMECertificateDomain)))
+		Expect(err).NotTo(HaveOccurred())
+		f.WaitCertificateIssuedValid(cert)
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Copyright 2017 Jetstack Ltd.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/issuer/ocsp"
+	"github.com/jetstack/cert-manager/test/e2e/framework"
+	"github.com/jetstack/cert-manager/test/util"
+)
+
+var _ = framework.CertManagerDescribe("ACME Certificate (OCSP must-staple)", func() {
+	f := framework.NewDefaultFramework("create-acme-certificate-ocsp-staple")
+
+	issuerName := "test-acme-ocsp-issuer"
+	certificateName := "test-acme-ocsp-certificate"
+	certificateSecretName := "test-acme-ocsp-certificate"
+	testingACMEOCSPPrivateKey := "test-acme-ocsp-private-key"
+
+	BeforeEach(func() {
+		By("Creating an Issuer")
+		_, err := f.CertManagerClientSet.CertmanagerV1alpha1().Issuers(f.Namespace.Name).Create(util.NewCertManagerACMEIssuer(issuerName, framework.TestContext.ACMEURL, testingACMEEmail, testingACMEOCSPPrivateKey, 0, 0))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		By("Cleaning up")
+		f.CertManagerClientSet.CertmanagerV1alpha1().Issuers(f.Namespace.Name).Delete(issuerName, nil)
+		f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Delete(testingACMEOCSPPrivateKey, nil)
+	})
+
+	It("should staple an OCSP response into the TLS secret and refresh it before expiry", func() {
+		By("Creating a Certificate with ocspMustStaple set")
+		cert, err := f.CertManagerClientSet.CertmanagerV1alpha1().Certificates(f.Namespace.Name).Create(util.NewCertManagerACMECertificateWithOCSPMustStaple(certificateName, certificateSecretName, issuerName, acmeIngressClass, util.ACMECertificateDomain))
+		Expect(err).NotTo(HaveOccurred())
+		f.WaitCertificateIssuedValid(cert)
+
+		By("Verifying a staple is present in the TLS secret")
+		s, err := f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Get(certificateSecretName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKey(ocsp.SecretOCSPStapleKey))
+		firstStaple := s.Data[ocsp.SecretOCSPStapleKey]
+
+		By("Waiting for the staple to be refreshed")
+		Eventually(func() ([]byte, error) {
+			s, err := f.KubeClientSet.CoreV1().Secrets(f.Namespace.Name).Get(certificateSecretName, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return s.Data[ocsp.SecretOCSPStapleKey], nil
+		}, time.Minute*10, time.Second*5).ShouldNot(Equal(firstStaple))
+	})
+})